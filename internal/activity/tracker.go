@@ -0,0 +1,110 @@
+package activity
+
+import (
+	"sync"
+	"time"
+
+	"github.com/robotn/gohook"
+)
+
+// State describes whether real user input was recently observed.
+type State int
+
+const (
+	// Active means a real keyboard/mouse event arrived within the idle
+	// threshold.
+	Active State = iota
+	// Idle means no real input has been observed for at least the idle
+	// threshold.
+	Idle
+)
+
+// Heartbeat is emitted on every sample tick with the tracker's current
+// assessment of user activity.
+type Heartbeat struct {
+	State State
+	At    time.Time
+}
+
+const sampleInterval = 1 * time.Second
+
+// Tracker samples real keyboard/mouse input via a global hook and emits
+// Active/Idle heartbeats on Heartbeats().
+type Tracker struct {
+	idleThresholdMu sync.RWMutex
+	idleThreshold   time.Duration
+
+	heartbeats chan Heartbeat
+	lastInput  chan time.Time
+	stop       chan struct{}
+}
+
+// NewTracker creates a Tracker that considers the user idle once
+// idleThreshold has elapsed since the last observed input event.
+func NewTracker(idleThreshold time.Duration) *Tracker {
+	return &Tracker{
+		idleThreshold: idleThreshold,
+		heartbeats:    make(chan Heartbeat),
+		lastInput:     make(chan time.Time, 1),
+		stop:          make(chan struct{}),
+	}
+}
+
+// Heartbeats returns the channel on which Active/Idle heartbeats are
+// delivered, one per sample interval. The channel is closed once Start
+// returns after Stop is called, so callers can safely range over it.
+func (t *Tracker) Heartbeats() <-chan Heartbeat {
+	return t.heartbeats
+}
+
+// SetIdleThreshold updates the idle threshold used to classify heartbeats.
+func (t *Tracker) SetIdleThreshold(d time.Duration) {
+	t.idleThresholdMu.Lock()
+	t.idleThreshold = d
+	t.idleThresholdMu.Unlock()
+}
+
+// currentIdleThreshold returns the idle threshold currently used to
+// classify heartbeats.
+func (t *Tracker) currentIdleThreshold() time.Duration {
+	t.idleThresholdMu.RLock()
+	defer t.idleThresholdMu.RUnlock()
+	return t.idleThreshold
+}
+
+// Start begins listening for real input events and sampling activity state.
+// It blocks until Stop is called, so callers should run it in its own
+// goroutine.
+func (t *Tracker) Start() {
+	events := hook.Start()
+	defer hook.End()
+	defer close(t.heartbeats)
+
+	lastInput := time.Now()
+	ticker := time.NewTicker(sampleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-events:
+			lastInput = time.Now()
+		case now := <-ticker.C:
+			state := Active
+			if now.Sub(lastInput) >= t.currentIdleThreshold() {
+				state = Idle
+			}
+			select {
+			case t.heartbeats <- Heartbeat{State: state, At: now}:
+			case <-t.stop:
+				return
+			}
+		case <-t.stop:
+			return
+		}
+	}
+}
+
+// Stop terminates the tracker's event loop.
+func (t *Tracker) Stop() {
+	close(t.stop)
+}
@@ -0,0 +1,312 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const configFileName = "config.json"
+
+// Config holds user preferences that are persisted to disk between runs.
+type Config struct {
+	Enabled              bool     `json:"enabled"`
+	SleepInterval        int      `json:"sleep_interval"`
+	WorkingHours         []string `json:"working_hours"`
+	WorkingHoursInterval string   `json:"working_hours_interval"`
+	ActivityAware        bool     `json:"activity_aware"`
+	IdleThresholdSec     int      `json:"idle_threshold_sec"`
+	CustomIntervals      []int    `json:"custom_intervals"`
+	CustomWorkingHours   []string `json:"custom_working_hours"`
+	GitRepo              string   `json:"git_repo"`
+
+	// mu guards the fields above that are read and written concurrently by
+	// the tray's click-handler goroutine, Controller and the status updater
+	// (Enabled, ActivityAware, IdleThresholdSec, SleepInterval,
+	// WorkingHoursInterval).
+	mu   sync.RWMutex
+	path string
+}
+
+// Default returns the out-of-the-box configuration used on first run.
+func Default() *Config {
+	return &Config{
+		Enabled:       true,
+		SleepInterval: 30,
+		WorkingHours: []string{
+			"09:00-18:00",
+			"00:00-23:59",
+		},
+		WorkingHoursInterval: "00:00-23:59",
+		ActivityAware:        false,
+		IdleThresholdSec:     120,
+		GitRepo:              "https://github.com/sonjek/mouse-stay-up",
+	}
+}
+
+// Load reads the config file from the user's config directory, falling back
+// to Default() if it does not exist yet.
+func Load() (*Config, error) {
+	path, err := configPath()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := Default()
+	cfg.path = path
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, err
+	}
+	cfg.path = path
+
+	return cfg, nil
+}
+
+// Save writes the current config to disk.
+func (c *Config) Save() error {
+	if c.path == "" {
+		path, err := configPath()
+		if err != nil {
+			return err
+		}
+		c.path = path
+	}
+
+	c.mu.RLock()
+	data, err := json.MarshalIndent(c, "", "  ")
+	c.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(c.path, data, 0o644)
+}
+
+// SleepIntervalSeconds returns the currently configured sleep interval.
+func (c *Config) SleepIntervalSeconds() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.SleepInterval
+}
+
+// SetSleepIntervalSec updates the sleep interval and persists the change.
+func (c *Config) SetSleepIntervalSec(sec int) {
+	c.mu.Lock()
+	c.SleepInterval = sec
+	c.mu.Unlock()
+	_ = c.Save()
+}
+
+// CurrentWorkingHoursInterval returns the currently selected working hours
+// range.
+func (c *Config) CurrentWorkingHoursInterval() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.WorkingHoursInterval
+}
+
+// SetWorkingHoursInterval updates the selected working hours range and
+// persists the change.
+func (c *Config) SetWorkingHoursInterval(interval string) {
+	c.mu.Lock()
+	c.WorkingHoursInterval = interval
+	c.mu.Unlock()
+	_ = c.Save()
+}
+
+// IsActivityAware reports whether activity-aware mode is currently on.
+func (c *Config) IsActivityAware() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.ActivityAware
+}
+
+// SetActivityAware toggles activity-aware mode and persists the change.
+func (c *Config) SetActivityAware(enabled bool) {
+	c.mu.Lock()
+	c.ActivityAware = enabled
+	c.mu.Unlock()
+	_ = c.Save()
+}
+
+// IdleThresholdSeconds returns the currently configured idle threshold.
+func (c *Config) IdleThresholdSeconds() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.IdleThresholdSec
+}
+
+// SetIdleThresholdSec updates the idle threshold, in seconds, after which
+// the cursor is considered idle again and persists the change.
+func (c *Config) SetIdleThresholdSec(sec int) {
+	c.mu.Lock()
+	c.IdleThresholdSec = sec
+	c.mu.Unlock()
+	_ = c.Save()
+}
+
+// IsEnabled reports whether the mouse mover is currently enabled.
+func (c *Config) IsEnabled() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Enabled
+}
+
+// SetEnabled toggles whether the mouse mover is enabled.
+func (c *Config) SetEnabled(enabled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Enabled = enabled
+}
+
+// AddCustomInterval appends a user-defined interval, in seconds, to
+// CustomIntervals and persists it. A duplicate of an existing custom
+// interval is a no-op.
+func (c *Config) AddCustomInterval(sec int) {
+	c.mu.Lock()
+	for _, existing := range c.CustomIntervals {
+		if existing == sec {
+			c.mu.Unlock()
+			return
+		}
+	}
+	c.CustomIntervals = append(c.CustomIntervals, sec)
+	c.mu.Unlock()
+	_ = c.Save()
+}
+
+// RemoveCustomInterval drops a previously added custom interval and
+// persists the change.
+func (c *Config) RemoveCustomInterval(sec int) {
+	c.mu.Lock()
+	for i, existing := range c.CustomIntervals {
+		if existing == sec {
+			c.CustomIntervals = append(c.CustomIntervals[:i], c.CustomIntervals[i+1:]...)
+			c.mu.Unlock()
+			_ = c.Save()
+			return
+		}
+	}
+	c.mu.Unlock()
+}
+
+// AddCustomWorkingHours appends a user-defined "HH:MM-HH:MM" range to
+// CustomWorkingHours and persists it. A duplicate of an existing custom
+// range is a no-op.
+func (c *Config) AddCustomWorkingHours(hoursRange string) {
+	c.mu.Lock()
+	for _, existing := range c.CustomWorkingHours {
+		if existing == hoursRange {
+			c.mu.Unlock()
+			return
+		}
+	}
+	c.CustomWorkingHours = append(c.CustomWorkingHours, hoursRange)
+	c.mu.Unlock()
+	_ = c.Save()
+}
+
+// RemoveCustomWorkingHours drops a previously added custom working hours
+// range and persists the change.
+func (c *Config) RemoveCustomWorkingHours(hoursRange string) {
+	c.mu.Lock()
+	for i, existing := range c.CustomWorkingHours {
+		if existing == hoursRange {
+			c.CustomWorkingHours = append(c.CustomWorkingHours[:i], c.CustomWorkingHours[i+1:]...)
+			c.mu.Unlock()
+			_ = c.Save()
+			return
+		}
+	}
+	c.mu.Unlock()
+}
+
+// ValidateWorkingHoursRange reports an error if s is not a well-formed
+// "HH:MM-HH:MM" range.
+func ValidateWorkingHoursRange(s string) error {
+	if _, _, ok := parseHoursRange(s); !ok {
+		return fmt.Errorf("invalid working hours range %q, expected HH:MM-HH:MM", s)
+	}
+	return nil
+}
+
+// IsWithinWorkingHours reports whether t falls inside the currently
+// selected WorkingHoursInterval ("HH:MM-HH:MM"). An interval that doesn't
+// parse is treated as "always within", so a bad value never pauses the
+// controller outright.
+func (c *Config) IsWithinWorkingHours(t time.Time) bool {
+	start, end, ok := parseHoursRange(c.CurrentWorkingHoursInterval())
+	if !ok {
+		return true
+	}
+
+	minutes := t.Hour()*60 + t.Minute()
+	if end >= start {
+		return minutes >= start && minutes <= end
+	}
+	// Range wraps past midnight, e.g. "22:00-06:00".
+	return minutes >= start || minutes <= end
+}
+
+func parseHoursRange(interval string) (startMinutes, endMinutes int, ok bool) {
+	parts := strings.SplitN(interval, "-", 2)
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+
+	start, ok := parseClock(parts[0])
+	if !ok {
+		return 0, 0, false
+	}
+	end, ok := parseClock(parts[1])
+	if !ok {
+		return 0, 0, false
+	}
+
+	return start, end, true
+}
+
+func parseClock(s string) (minutes int, ok bool) {
+	parts := strings.SplitN(s, ":", 2)
+	if len(parts) != 2 {
+		return 0, false
+	}
+
+	hour, err := strconv.Atoi(parts[0])
+	if err != nil || hour < 0 || hour > 23 {
+		return 0, false
+	}
+	minute, err := strconv.Atoi(parts[1])
+	if err != nil || minute < 0 || minute > 59 {
+		return 0, false
+	}
+
+	return hour*60 + minute, true
+}
+
+func configPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "mouse-stay-up", configFileName), nil
+}
@@ -0,0 +1,251 @@
+package mouse
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/go-vgo/robotgo"
+
+	"github.com/sonjek/mouse-stay-up/internal/activity"
+	"github.com/sonjek/mouse-stay-up/internal/config"
+)
+
+const jiggleRadius = 2
+
+// randomIntervalMin and randomIntervalMax bound the randomized interval
+// used when SleepInterval is the "10-60 sec" sentinel (-1).
+const (
+	randomIntervalMin = 10
+	randomIntervalMax = 60
+)
+
+// resolveInterval turns the -1 "10-60 sec" sentinel into an actual
+// randomized duration; any configured positive value passes through
+// unchanged. This keeps non-positive durations from ever reaching
+// time.NewTicker, which panics on them.
+func resolveInterval(configuredSec int) time.Duration {
+	if configuredSec <= 0 {
+		span := randomIntervalMax - randomIntervalMin + 1
+		return time.Duration(randomIntervalMin+rand.Intn(span)) * time.Second
+	}
+	return time.Duration(configuredSec) * time.Second
+}
+
+// cursorMover abstracts the platform cursor so tests can inject a mock
+// instead of driving the real pointer.
+type cursorMover interface {
+	Location() (x, y int)
+	Move(x, y int)
+}
+
+type robotgoCursor struct{}
+
+func (robotgoCursor) Location() (int, int) { return robotgo.Location() }
+func (robotgoCursor) Move(x, y int)        { robotgo.Move(x, y) }
+
+// ticker abstracts time.Ticker so tests can inject a fake one instead of
+// waiting on real time.
+type ticker interface {
+	Chan() <-chan time.Time
+	Stop()
+}
+
+type realTicker struct{ *time.Ticker }
+
+func (r *realTicker) Chan() <-chan time.Time { return r.C }
+
+func newRealTicker(d time.Duration) ticker {
+	return &realTicker{time.NewTicker(d)}
+}
+
+var (
+	instance     *Controller
+	instanceOnce sync.Once
+)
+
+// Controller is the process-wide singleton that drives the periodic mouse
+// movement based on the current config, with an explicit Start/Stop/Quit
+// lifecycle so repeated Enable/Disable toggles don't leak goroutines.
+type Controller struct {
+	config    *config.Config
+	cursor    cursorMover
+	newTicker func(time.Duration) ticker
+
+	mu      sync.Mutex
+	running bool
+	quit    chan struct{}
+
+	lastActiveMu sync.Mutex
+	lastActive   time.Time
+
+	nextJiggleMu sync.Mutex
+	nextJiggle   time.Time
+}
+
+// GetInstance returns the process-wide Controller, creating it on first
+// call bound to cfg. Subsequent calls ignore cfg and return the same
+// instance.
+func GetInstance(cfg *config.Config) *Controller {
+	instanceOnce.Do(func() {
+		instance = newController(cfg, robotgoCursor{}, newRealTicker)
+	})
+	return instance
+}
+
+func newController(cfg *config.Config, cursor cursorMover, newTicker func(time.Duration) ticker) *Controller {
+	return &Controller{
+		config:    cfg,
+		cursor:    cursor,
+		newTicker: newTicker,
+	}
+}
+
+// IsRunning reports whether the mouse-moving loop is currently active.
+func (c *Controller) IsRunning() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.running
+}
+
+// Start begins the mouse-moving loop in its own goroutine. It is a no-op
+// if the controller is already running.
+func (c *Controller) Start() {
+	c.mu.Lock()
+	if c.running {
+		c.mu.Unlock()
+		return
+	}
+	c.running = true
+	c.quit = make(chan struct{})
+	quit := c.quit
+	c.mu.Unlock()
+
+	go c.MoveMouse(quit)
+}
+
+// Stop signals the mouse-moving loop to exit. It is a no-op if the
+// controller isn't running.
+func (c *Controller) Stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.running {
+		return
+	}
+	c.running = false
+	close(c.quit)
+}
+
+// Quit stops the controller. It is the lifecycle method Tray calls from
+// onExit, distinct from Stop so shutdown intent reads clearly at the call
+// site even though the behavior is identical today.
+func (c *Controller) Quit() {
+	c.Stop()
+}
+
+// WatchActivity consumes heartbeats from tracker, remembering the most
+// recent moment a real input event was observed. Call this once before
+// starting the controller when activity-aware mode is enabled.
+func (c *Controller) WatchActivity(tracker *activity.Tracker) {
+	go func() {
+		for hb := range tracker.Heartbeats() {
+			if hb.State != activity.Active {
+				continue
+			}
+			c.lastActiveMu.Lock()
+			c.lastActive = hb.At
+			c.lastActiveMu.Unlock()
+		}
+	}()
+}
+
+// MoveMouse jiggles the cursor in a small circle on every tick until quit
+// is closed. While activity-aware mode is on, jiggles are suppressed for
+// as long as real user input keeps arriving within the configured idle
+// threshold. Changing the configured interval while running takes effect
+// starting with the next tick, rather than requiring a disable/re-enable.
+func (c *Controller) MoveMouse(quit <-chan struct{}) {
+	configuredSec := c.config.SleepIntervalSeconds()
+	interval := resolveInterval(configuredSec)
+	t := c.newTicker(interval)
+	defer func() { t.Stop() }()
+
+	for {
+		select {
+		case <-quit:
+			return
+		case <-t.Chan():
+			if !c.suppressedByActivity() {
+				c.jiggle()
+			}
+
+			c.nextJiggleMu.Lock()
+			c.nextJiggle = time.Now().Add(interval)
+			c.nextJiggleMu.Unlock()
+
+			if sec := c.config.SleepIntervalSeconds(); sec != configuredSec {
+				configuredSec = sec
+				interval = resolveInterval(configuredSec)
+				t.Stop()
+				t = c.newTicker(interval)
+			}
+		}
+	}
+}
+
+// IsSuppressed reports whether the next jiggle would currently be skipped
+// because a real heartbeat arrived within the idle threshold.
+func (c *Controller) IsSuppressed() bool {
+	return c.suppressedByActivity()
+}
+
+// NextJiggleIn returns how long until the next scheduled jiggle. It returns
+// zero once the controller is stopped or before the first jiggle has run.
+func (c *Controller) NextJiggleIn() time.Duration {
+	c.nextJiggleMu.Lock()
+	next := c.nextJiggle
+	c.nextJiggleMu.Unlock()
+
+	if next.IsZero() {
+		return 0
+	}
+
+	if remaining := time.Until(next); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// suppressedByActivity reports whether a real heartbeat arrived within the
+// configured idle threshold, meaning synthetic jiggles should be skipped.
+func (c *Controller) suppressedByActivity() bool {
+	if !c.config.IsActivityAware() {
+		return false
+	}
+
+	c.lastActiveMu.Lock()
+	lastActive := c.lastActive
+	c.lastActiveMu.Unlock()
+
+	if lastActive.IsZero() {
+		return false
+	}
+
+	threshold := time.Duration(c.config.IdleThresholdSeconds()) * time.Second
+	return time.Since(lastActive) < threshold
+}
+
+// jiggle nudges the cursor around its current position and back.
+func (c *Controller) jiggle() {
+	x, y := c.cursor.Location()
+
+	for angle := 0; angle < 360; angle += 90 {
+		radians := float64(angle) * math.Pi / 180
+		dx := int(jiggleRadius * math.Cos(radians))
+		dy := int(jiggleRadius * math.Sin(radians))
+		c.cursor.Move(x+dx, y+dy)
+	}
+
+	c.cursor.Move(x, y)
+}
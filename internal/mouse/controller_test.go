@@ -0,0 +1,91 @@
+package mouse
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sonjek/mouse-stay-up/internal/config"
+)
+
+// fakeTicker lets the test drive ticks by hand instead of waiting on real
+// time.
+type fakeTicker struct {
+	c chan time.Time
+}
+
+func (f *fakeTicker) Chan() <-chan time.Time { return f.c }
+func (f *fakeTicker) Stop()                  {}
+
+// mockCursor records jiggles without touching the real pointer.
+type mockCursor struct {
+	mu    sync.Mutex
+	moves int
+}
+
+func (m *mockCursor) Location() (int, int) { return 0, 0 }
+
+func (m *mockCursor) Move(int, int) {
+	m.mu.Lock()
+	m.moves++
+	m.mu.Unlock()
+}
+
+func TestControllerStartStopDoesNotLeakGoroutines(t *testing.T) {
+	cfg := &config.Config{Enabled: true, SleepInterval: 1}
+	cursor := &mockCursor{}
+	fake := &fakeTicker{c: make(chan time.Time)}
+
+	ctrl := newController(cfg, cursor, func(time.Duration) ticker { return fake })
+
+	before := runtime.NumGoroutine()
+
+	for i := 0; i < 10; i++ {
+		ctrl.Start()
+		if !ctrl.IsRunning() {
+			t.Fatalf("iteration %d: expected controller to report running after Start", i)
+		}
+
+		fake.c <- time.Now() // drive one jiggle tick through the loop
+
+		ctrl.Stop()
+		if ctrl.IsRunning() {
+			t.Fatalf("iteration %d: expected controller to report stopped after Stop", i)
+		}
+	}
+
+	// Give the stopped goroutines a moment to actually return.
+	time.Sleep(50 * time.Millisecond)
+
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("goroutine count grew from %d to %d after repeated Start/Stop toggles", before, after)
+	}
+
+	cursor.mu.Lock()
+	moves := cursor.moves
+	cursor.mu.Unlock()
+	if moves == 0 {
+		t.Error("expected at least one jiggle to have been driven through the loop")
+	}
+}
+
+func TestControllerStartIsIdempotentWhileRunning(t *testing.T) {
+	cfg := &config.Config{Enabled: true, SleepInterval: 1}
+	cursor := &mockCursor{}
+	fake := &fakeTicker{c: make(chan time.Time)}
+
+	ctrl := newController(cfg, cursor, func(time.Duration) ticker { return fake })
+
+	ctrl.Start()
+	ctrl.Start() // should not spawn a second loop or panic on a double quit channel
+
+	if !ctrl.IsRunning() {
+		t.Fatal("expected controller to report running")
+	}
+
+	ctrl.Quit()
+	if ctrl.IsRunning() {
+		t.Fatal("expected Quit to stop the controller")
+	}
+}
@@ -4,21 +4,38 @@ import (
 	"embed"
 	"fmt"
 	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/getlantern/systray"
+	"github.com/ncruces/zenity"
 
+	"github.com/sonjek/mouse-stay-up/internal/activity"
 	"github.com/sonjek/mouse-stay-up/internal/config"
 	"github.com/sonjek/mouse-stay-up/internal/mouse"
 	"github.com/sonjek/mouse-stay-up/internal/utils"
 )
 
+// idleThresholds are the selectable "resume jiggling after" options offered
+// under the Activity-aware mode submenu, in seconds.
+var idleThresholds = []struct {
+	title   string
+	seconds int
+}{
+	{"30 sec", 30},
+	{"2 min", 120},
+	{"5 min", 300},
+}
+
 var (
-	//go:embed icon.png
-	iconFile embed.FS
+	//go:embed icon-active.png icon-paused.png
+	iconFiles embed.FS
 )
 
-func loadIcon() ([]byte, error) {
-	file, err := iconFile.Open("icon.png")
+func loadIcon(name string) ([]byte, error) {
+	file, err := iconFiles.Open(name)
 	if err != nil {
 		return nil, err
 	}
@@ -32,11 +49,26 @@ func loadIcon() ([]byte, error) {
 	return data, nil
 }
 
+const statusUpdateInterval = 1 * time.Second
+
 type Tray struct {
-	mouseController       *mouse.Controller
-	config                *config.Config
+	mouseController *mouse.Controller
+	config          *config.Config
+
+	// itemsMu guards intervalItems and workingHoursMenuItems, which are
+	// read from the event-loop goroutine (checkmark updates, lookups) and
+	// written from each custom item's own "Remove" goroutine (deletes).
+	itemsMu               sync.Mutex
 	intervalItems         map[int]*systray.MenuItem
 	workingHoursMenuItems map[string]*systray.MenuItem
+	idleThresholdItems    map[int]*systray.MenuItem
+
+	intervalParent     *systray.MenuItem
+	workingHoursParent *systray.MenuItem
+	intervalClicks     chan int
+	workingHoursClicks chan string
+
+	activityTracker *activity.Tracker
 }
 
 func NewTray(mouseController *mouse.Controller, config *config.Config) *Tray {
@@ -45,6 +77,7 @@ func NewTray(mouseController *mouse.Controller, config *config.Config) *Tray {
 		config:                config,
 		intervalItems:         make(map[int]*systray.MenuItem),
 		workingHoursMenuItems: make(map[string]*systray.MenuItem),
+		idleThresholdItems:    make(map[int]*systray.MenuItem),
 	}
 }
 
@@ -53,68 +86,119 @@ func (t *Tray) Run() {
 }
 
 func (t *Tray) onReady() {
-	icon, err := loadIcon()
+	activeIcon, err := loadIcon("icon-active.png")
+	if err != nil {
+		panic(fmt.Errorf("could not load icon due to error `%s`", err.Error()))
+	}
+	pausedIcon, err := loadIcon("icon-paused.png")
 	if err != nil {
 		panic(fmt.Errorf("could not load icon due to error `%s`", err.Error()))
 	}
 
 	// Set icon and tooltip for the systray icon
-	systray.SetTemplateIcon(icon, icon)
+	systray.SetTemplateIcon(activeIcon, activeIcon)
 	systray.SetTooltip("Enable or Disable periodic mouse movements")
 
+	// Create a dynamic, read-only item reflecting the controller's live state
+	mStatus := systray.AddMenuItem("Status", "Current state of the mouse mover")
+	mStatus.Disable()
+	systray.AddSeparator()
+
 	// Create menu items for enable/disable mouse movement, change sleep interval and exit
 	mEnable := systray.AddMenuItem("Enable", "Enable mouse movement")
 	mDisable := systray.AddMenuItem("Disable", "Disable mouse movement")
 	mInterval := systray.AddMenuItem("Check Interval", "Set mouse movement interval")
 	mWorkingHours := systray.AddMenuItem("Working hours", "Select a range of working hours")
+	mActivityAware := systray.AddMenuItem("Activity-aware mode", "Suppress jiggles while you're actually using the mouse or keyboard")
+	mIdleThreshold := mActivityAware.AddSubMenuItem("Resume after", "How long to wait after real input before jiggling again")
 	systray.AddSeparator()
 	mAbout := systray.AddMenuItem("About", "Open GitHub repo")
 	mQuit := systray.AddMenuItem("Quit", "Quit the application")
 
 	// Hide the enable option since it's already enabled by default
-	if t.config.Enabled {
+	if t.config.IsEnabled() {
 		mEnable.Hide()
 	} else {
 		mDisable.Hide()
 	}
 
+	t.intervalParent = mInterval
+	t.workingHoursParent = mWorkingHours
+
 	// Add interval selection submenu items
 	t.addIntervalItem(mInterval, "10-60 sec", -1)
 	t.addIntervalItem(mInterval, "30 sec", 30)
 	t.addIntervalItem(mInterval, "60 sec", 60)
 
-	// Mark the default interval
-	t.intervalItems[int(t.config.SleepInterval)].Check()
-
 	// Create a channel to listen for interval item clicks
 	intervalClicks := t.createIntervalClicksChannel()
 
+	// Restore any previously saved custom intervals before marking the
+	// default, since the saved interval may itself be a custom one.
+	for _, seconds := range t.config.CustomIntervals {
+		_ = t.AddCustomInterval(seconds)
+	}
+
+	// Mark the default interval
+	if item, ok := t.intervalItem(int(t.config.SleepIntervalSeconds())); ok {
+		item.Check()
+	}
+
+	mCustomInterval := mInterval.AddSubMenuItem("Custom…", "Enter a custom interval in seconds")
+
 	// Add interval selection submenu items
 	for _, hours := range t.config.WorkingHours {
 		t.addWorkingHoursItems(mWorkingHours, hours)
 	}
 
+	workingHoursIntervalClicks := t.createWorkingHoursIntervalClicksChannel()
+
+	// Restore any previously saved custom working hours ranges before
+	// marking the default, since the saved range may itself be a custom one.
+	for _, hoursRange := range t.config.CustomWorkingHours {
+		_ = t.AddCustomWorkingHours(hoursRange)
+	}
+
 	// Set a marker for the default working hours interval
-	t.workingHoursMenuItems[t.config.WorkingHoursInterval].Check()
+	if item, ok := t.workingHoursItem(t.config.CurrentWorkingHoursInterval()); ok {
+		item.Check()
+	}
 
-	workingHoursIntervalClicks := t.createWorkingHoursIntervalClicksChannel()
+	mCustomWorkingHours := mWorkingHours.AddSubMenuItem("Custom…", "Enter a custom HH:MM-HH:MM range")
+
+	// Add idle threshold selection submenu items
+	for _, threshold := range idleThresholds {
+		t.addIdleThresholdItem(mIdleThreshold, threshold.title, threshold.seconds)
+	}
+
+	// Mark the default idle threshold and activity-aware toggle
+	t.idleThresholdItems[t.config.IdleThresholdSeconds()].Check()
+	if t.config.IsActivityAware() {
+		mActivityAware.Check()
+		t.startActivityTracker()
+	}
+
+	idleThresholdClicks := t.createIdleThresholdClicksChannel()
+
+	go t.statusUpdater(mStatus, activeIcon, pausedIcon)
 
 	go func() {
 		for {
 			select {
 			case <-mEnable.ClickedCh:
-				t.config.Enabled = true
+				t.config.SetEnabled(true)
 				mEnable.Hide()
 				mDisable.Show()
 				mInterval.Enable()
 				mWorkingHours.Enable()
-				go t.mouseController.MoveMouse()
+				t.mouseController.Start()
 			case <-mDisable.ClickedCh:
-				t.config.Enabled = false
+				t.config.SetEnabled(false)
 				mDisable.Hide()
 				mEnable.Show()
 				mInterval.Disable()
 				mWorkingHours.Disable()
+				t.mouseController.Stop()
 			case interval := <-intervalClicks:
 				// When an interval item is clicked, update the sleep interval and checkmarks
 				t.config.SetSleepIntervalSec(interval)
@@ -122,7 +206,28 @@ func (t *Tray) onReady() {
 			case workingHoursInterval := <-workingHoursIntervalClicks:
 				// When an hours interval item is clicked, update the workingHoursInterval interval and checkmarks
 				t.config.SetWorkingHoursInterval(workingHoursInterval)
-				t.updateNightModeIntervalChecks(t.config.WorkingHoursInterval)
+				t.updateNightModeIntervalChecks(t.config.CurrentWorkingHoursInterval())
+			case <-mActivityAware.ClickedCh:
+				if t.config.IsActivityAware() {
+					t.config.SetActivityAware(false)
+					mActivityAware.Uncheck()
+					t.stopActivityTracker()
+				} else {
+					t.config.SetActivityAware(true)
+					mActivityAware.Check()
+					t.startActivityTracker()
+				}
+			case threshold := <-idleThresholdClicks:
+				// When an idle threshold item is clicked, update the config and checkmarks
+				t.config.SetIdleThresholdSec(threshold)
+				t.updateIdleThresholdChecks(threshold)
+				if t.activityTracker != nil {
+					t.activityTracker.SetIdleThreshold(time.Duration(threshold) * time.Second)
+				}
+			case <-mCustomInterval.ClickedCh:
+				t.promptCustomInterval()
+			case <-mCustomWorkingHours.ClickedCh:
+				t.promptCustomWorkingHours()
 			case <-mAbout.ClickedCh:
 				utils.OpenWebPage(t.config.GitRepo)
 			case <-mQuit.ClickedCh:
@@ -133,32 +238,131 @@ func (t *Tray) onReady() {
 	}()
 
 	// Start moving the mouse in a circle immediately if enabled
-	if t.config.Enabled {
-		go t.mouseController.MoveMouse()
+	if t.config.IsEnabled() {
+		t.mouseController.Start()
 	}
 }
 
 // Adds a submenu item for selecting a sleep interval
 func (t *Tray) addIntervalItem(parent *systray.MenuItem, title string, interval int) {
-	t.intervalItems[interval] = parent.AddSubMenuItem(title, "Set interval to "+title)
+	item := parent.AddSubMenuItem(title, "Set interval to "+title)
+
+	t.itemsMu.Lock()
+	t.intervalItems[interval] = item
+	t.itemsMu.Unlock()
+}
+
+// intervalItem looks up the menu item for interval, reporting false if it
+// hasn't been added yet (e.g. a persisted custom interval not yet restored).
+func (t *Tray) intervalItem(interval int) (*systray.MenuItem, bool) {
+	t.itemsMu.Lock()
+	defer t.itemsMu.Unlock()
+	item, ok := t.intervalItems[interval]
+	return item, ok
 }
 
 // Creates and returns a channel that listens to all interval item clicks
 func (t *Tray) createIntervalClicksChannel() <-chan int {
-	clicks := make(chan int)
+	t.intervalClicks = make(chan int)
+
+	t.itemsMu.Lock()
+	items := make(map[int]*systray.MenuItem, len(t.intervalItems))
 	for interval, item := range t.intervalItems {
-		go func(interval int, item *systray.MenuItem) {
-			for {
-				<-item.ClickedCh
-				clicks <- interval
+		items[interval] = item
+	}
+	t.itemsMu.Unlock()
+
+	for interval, item := range items {
+		// Built-in items are never removed, so they have no done channel.
+		t.watchIntervalClick(interval, item, nil)
+	}
+	return t.intervalClicks
+}
+
+// watchIntervalClick forwards clicks on item onto t.intervalClicks, tagged
+// with the interval it represents, until done is closed.
+func (t *Tray) watchIntervalClick(interval int, item *systray.MenuItem, done <-chan struct{}) {
+	go func(interval int, item *systray.MenuItem, done <-chan struct{}) {
+		for {
+			select {
+			case <-item.ClickedCh:
+				t.intervalClicks <- interval
+			case <-done:
+				return
 			}
-		}(interval, item)
+		}
+	}(interval, item, done)
+}
+
+// promptCustomInterval asks the user for a custom interval, in seconds,
+// through a native input dialog and adds it on success.
+func (t *Tray) promptCustomInterval() {
+	input, err := zenity.Entry("Interval in seconds:", zenity.Title("Custom check interval"))
+	if err != nil {
+		// User cancelled the dialog.
+		return
 	}
-	return clicks
+
+	seconds, err := strconv.Atoi(strings.TrimSpace(input))
+	if err != nil || seconds <= 0 {
+		_ = zenity.Error("Enter a positive number of seconds.", zenity.Title("Invalid interval"))
+		return
+	}
+
+	if err := t.AddCustomInterval(seconds); err != nil {
+		_ = zenity.Error(err.Error(), zenity.Title("Invalid interval"))
+	}
+}
+
+// AddCustomInterval validates, persists and appends a new interval option
+// to the Check Interval submenu, wiring up its click and removal handlers
+// the same way the built-in options are wired.
+func (t *Tray) AddCustomInterval(seconds int) error {
+	if seconds <= 0 {
+		return fmt.Errorf("interval must be a positive number of seconds, got %d", seconds)
+	}
+	if _, exists := t.intervalItem(seconds); exists {
+		return nil
+	}
+
+	t.config.AddCustomInterval(seconds)
+
+	title := fmt.Sprintf("%d sec", seconds)
+	item := t.intervalParent.AddSubMenuItem(title, "Set interval to "+title)
+
+	t.itemsMu.Lock()
+	t.intervalItems[seconds] = item
+	t.itemsMu.Unlock()
+
+	done := make(chan struct{})
+	t.watchIntervalClick(seconds, item, done)
+	t.watchRemoveCustomInterval(seconds, item, done)
+
+	return nil
+}
+
+// watchRemoveCustomInterval adds a "Remove" secondary submenu item under a
+// custom interval and, once clicked, hides both items, drops the config
+// entry and closes done so the goroutine watching the item's clicks exits.
+func (t *Tray) watchRemoveCustomInterval(seconds int, item *systray.MenuItem, done chan struct{}) {
+	remove := item.AddSubMenuItem("Remove", "Remove this custom interval")
+	go func() {
+		<-remove.ClickedCh
+		item.Hide()
+
+		t.itemsMu.Lock()
+		delete(t.intervalItems, seconds)
+		t.itemsMu.Unlock()
+
+		t.config.RemoveCustomInterval(seconds)
+		close(done)
+	}()
 }
 
 // Updates the checkmarks for interval selection
 func (t *Tray) updateIntervalChecks(selectedInterval int) {
+	t.itemsMu.Lock()
+	defer t.itemsMu.Unlock()
 	for interval, item := range t.intervalItems {
 		if interval == selectedInterval {
 			item.Check()
@@ -168,27 +372,212 @@ func (t *Tray) updateIntervalChecks(selectedInterval int) {
 	}
 }
 
+// statusUpdater ticks once a second, recomputing the current state from
+// config and mouseController, and reflects it in the tray tooltip, the
+// Status menu item and the template icon.
+func (t *Tray) statusUpdater(mStatus *systray.MenuItem, activeIcon, pausedIcon []byte) {
+	ticker := time.NewTicker(statusUpdateInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		status := t.statusText()
+
+		systray.SetTooltip("MSU: " + status)
+		mStatus.SetTitle("Status: " + status)
+		mStatus.SetTooltip(status)
+
+		if t.isPaused() {
+			systray.SetTemplateIcon(pausedIcon, pausedIcon)
+		} else {
+			systray.SetTemplateIcon(activeIcon, activeIcon)
+		}
+	}
+}
+
+// statusText renders a short human-readable summary of the controller's
+// current state, e.g. "active, next jiggle in 27s".
+func (t *Tray) statusText() string {
+	if !t.config.IsEnabled() {
+		return "paused (disabled)"
+	}
+	if !t.config.IsWithinWorkingHours(time.Now()) {
+		return fmt.Sprintf("paused (outside working hours %s)", t.config.CurrentWorkingHoursInterval())
+	}
+	if t.mouseController.IsSuppressed() {
+		return "suppressed (user active)"
+	}
+
+	return fmt.Sprintf("active, next jiggle in %ds", int(t.mouseController.NextJiggleIn().Seconds()))
+}
+
+// isPaused reports whether jiggles are not currently happening, for the
+// purposes of picking the active vs. paused tray icon.
+func (t *Tray) isPaused() bool {
+	return !t.config.IsEnabled() || !t.config.IsWithinWorkingHours(time.Now()) || t.mouseController.IsSuppressed()
+}
+
+// startActivityTracker spins up a Tracker for the configured idle threshold
+// and hands its heartbeats to the mouse controller.
+func (t *Tray) startActivityTracker() {
+	t.activityTracker = activity.NewTracker(time.Duration(t.config.IdleThresholdSeconds()) * time.Second)
+	t.mouseController.WatchActivity(t.activityTracker)
+	go t.activityTracker.Start()
+}
+
+// stopActivityTracker tears down the running Tracker, if any.
+func (t *Tray) stopActivityTracker() {
+	if t.activityTracker == nil {
+		return
+	}
+	t.activityTracker.Stop()
+	t.activityTracker = nil
+}
+
+// Adds a submenu item for selecting an idle threshold
+func (t *Tray) addIdleThresholdItem(parent *systray.MenuItem, title string, seconds int) {
+	t.idleThresholdItems[seconds] = parent.AddSubMenuItem(title, "Resume jiggling "+title+" after real input stops")
+}
+
+// Creates and returns a channel that listens to all idle threshold item clicks
+func (t *Tray) createIdleThresholdClicksChannel() <-chan int {
+	clicks := make(chan int)
+	for seconds, item := range t.idleThresholdItems {
+		go func(seconds int, item *systray.MenuItem) {
+			for {
+				<-item.ClickedCh
+				clicks <- seconds
+			}
+		}(seconds, item)
+	}
+	return clicks
+}
+
+// Updates the checkmarks for idle threshold selection
+func (t *Tray) updateIdleThresholdChecks(selectedSeconds int) {
+	for seconds, item := range t.idleThresholdItems {
+		if seconds == selectedSeconds {
+			item.Check()
+		} else {
+			item.Uncheck()
+		}
+	}
+}
+
 // Adds a submenu item for selecting a working hours interval
 func (t *Tray) addWorkingHoursItems(parent *systray.MenuItem, interval string) {
-	t.workingHoursMenuItems[interval] = parent.AddSubMenuItem(interval, interval)
+	item := parent.AddSubMenuItem(interval, interval)
+
+	t.itemsMu.Lock()
+	t.workingHoursMenuItems[interval] = item
+	t.itemsMu.Unlock()
+}
+
+// workingHoursItem looks up the menu item for interval, reporting false if
+// it hasn't been added yet (e.g. a persisted custom range not yet restored).
+func (t *Tray) workingHoursItem(interval string) (*systray.MenuItem, bool) {
+	t.itemsMu.Lock()
+	defer t.itemsMu.Unlock()
+	item, ok := t.workingHoursMenuItems[interval]
+	return item, ok
 }
 
 // Creates and returns a channel that listens to all working hours interval item clicks
 func (t *Tray) createWorkingHoursIntervalClicksChannel() <-chan string {
-	clicks := make(chan string)
+	t.workingHoursClicks = make(chan string)
+
+	t.itemsMu.Lock()
+	items := make(map[string]*systray.MenuItem, len(t.workingHoursMenuItems))
 	for interval, item := range t.workingHoursMenuItems {
-		go func(interval string, item *systray.MenuItem) {
-			for {
-				<-item.ClickedCh
-				clicks <- interval
+		items[interval] = item
+	}
+	t.itemsMu.Unlock()
+
+	for interval, item := range items {
+		// Built-in items are never removed, so they have no done channel.
+		t.watchWorkingHoursClick(interval, item, nil)
+	}
+	return t.workingHoursClicks
+}
+
+// watchWorkingHoursClick forwards clicks on item onto t.workingHoursClicks,
+// tagged with the range it represents, until done is closed.
+func (t *Tray) watchWorkingHoursClick(interval string, item *systray.MenuItem, done <-chan struct{}) {
+	go func(interval string, item *systray.MenuItem, done <-chan struct{}) {
+		for {
+			select {
+			case <-item.ClickedCh:
+				t.workingHoursClicks <- interval
+			case <-done:
+				return
 			}
-		}(interval, item)
+		}
+	}(interval, item, done)
+}
+
+// promptCustomWorkingHours asks the user for a custom "HH:MM-HH:MM" range
+// through a native input dialog and adds it on success.
+func (t *Tray) promptCustomWorkingHours() {
+	input, err := zenity.Entry("Working hours (HH:MM-HH:MM):", zenity.Title("Custom working hours"))
+	if err != nil {
+		// User cancelled the dialog.
+		return
 	}
-	return clicks
+
+	hoursRange := strings.TrimSpace(input)
+	if err := t.AddCustomWorkingHours(hoursRange); err != nil {
+		_ = zenity.Error(err.Error(), zenity.Title("Invalid working hours"))
+	}
+}
+
+// AddCustomWorkingHours validates, persists and appends a new working
+// hours option to the Working hours submenu, wiring up its click and
+// removal handlers the same way the built-in options are wired.
+func (t *Tray) AddCustomWorkingHours(hoursRange string) error {
+	if err := config.ValidateWorkingHoursRange(hoursRange); err != nil {
+		return err
+	}
+	if _, exists := t.workingHoursItem(hoursRange); exists {
+		return nil
+	}
+
+	t.config.AddCustomWorkingHours(hoursRange)
+
+	item := t.workingHoursParent.AddSubMenuItem(hoursRange, hoursRange)
+
+	t.itemsMu.Lock()
+	t.workingHoursMenuItems[hoursRange] = item
+	t.itemsMu.Unlock()
+
+	done := make(chan struct{})
+	t.watchWorkingHoursClick(hoursRange, item, done)
+	t.watchRemoveCustomWorkingHours(hoursRange, item, done)
+
+	return nil
+}
+
+// watchRemoveCustomWorkingHours adds a "Remove" secondary submenu item under
+// a custom working hours range and, once clicked, hides both items, drops
+// the config entry and closes done so the goroutine watching the item's
+// clicks exits.
+func (t *Tray) watchRemoveCustomWorkingHours(hoursRange string, item *systray.MenuItem, done chan struct{}) {
+	remove := item.AddSubMenuItem("Remove", "Remove this custom working hours range")
+	go func() {
+		<-remove.ClickedCh
+		item.Hide()
+
+		t.itemsMu.Lock()
+		delete(t.workingHoursMenuItems, hoursRange)
+		t.itemsMu.Unlock()
+
+		t.config.RemoveCustomWorkingHours(hoursRange)
+		close(done)
+	}()
 }
 
 // Updates the checkmarks for interval selection
 func (t *Tray) updateNightModeIntervalChecks(selectedInterval string) {
+	t.itemsMu.Lock()
+	defer t.itemsMu.Unlock()
 	for interval, item := range t.workingHoursMenuItems {
 		if interval == selectedInterval {
 			item.Check()
@@ -199,4 +588,6 @@ func (t *Tray) updateNightModeIntervalChecks(selectedInterval string) {
 }
 
 func (t *Tray) onExit() {
+	t.mouseController.Quit()
+	t.stopActivityTracker()
 }
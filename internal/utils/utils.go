@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"os/exec"
+	"runtime"
+)
+
+// OpenWebPage opens the given URL in the user's default browser.
+func OpenWebPage(url string) error {
+	var cmd string
+	var args []string
+
+	switch runtime.GOOS {
+	case "windows":
+		cmd = "rundll32"
+		args = []string{"url.dll,FileProtocolHandler", url}
+	case "darwin":
+		cmd = "open"
+		args = []string{url}
+	default:
+		cmd = "xdg-open"
+		args = []string{url}
+	}
+
+	return exec.Command(cmd, args...).Start()
+}